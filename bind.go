@@ -0,0 +1,128 @@
+package conf
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// reflectValue adapts an addressable reflect.Value to the value interface, so Bind can
+// register fields without a compile-time type parameter the way Var does.
+type reflectValue struct{ v reflect.Value }
+
+func (r reflectValue) Get() any                { return r.v.Interface() }
+func (r reflectValue) Type() reflect.Type      { return r.v.Type() }
+func (r reflectValue) Set(value any)           { r.v.Set(reflect.ValueOf(value)) }
+func (r reflectValue) Parse(text string) error { return parse(r.v, text, true) }
+
+// Bind walks v, a pointer to a struct, and registers every exported field with parser as
+// if by Var, using struct tags to configure each field:
+//
+//	type Config struct {
+//		DatabaseURL string        `conf:"DATABASE_URL,required" desc:"primary DB DSN"`
+//		Timeout     time.Duration `conf:"TIMEOUT" default:"5s"`
+//		DB          struct {
+//			URL string `conf:"URL"` // registered as DB_URL
+//		}
+//	}
+//
+// The conf tag's first comma-separated part is the variable name, defaulting to the
+// upper-cased field name when omitted, or skipping the field entirely when "-". Remaining
+// parts are flags: required, nonempty, skipempty, matching Required, NonEmpty, and
+// SkipEmpty. default and desc tags set the field's Default and Description.
+//
+// A nested struct field contributes its name as a prefix for its own fields (DB_URL
+// above); an anonymous (embedded) struct field does not.
+func Bind(parser Parser, v any) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("conf: Bind: v must be a non-nil pointer to a struct, got %T", v)
+	}
+	return bindStruct(parser, rv.Elem(), "")
+}
+
+func bindStruct(parser Parser, v reflect.Value, prefix string) error {
+	t := v.Type()
+
+	for i := 0; i < t.NumField(); i++ {
+		structField := t.Field(i)
+		if !structField.IsExported() {
+			continue
+		}
+
+		tagParts := strings.Split(structField.Tag.Get("conf"), ",")
+		name := tagParts[0]
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = strings.ToUpper(structField.Name)
+		}
+
+		fullName := name
+		if prefix != "" {
+			fullName = prefix + "_" + name
+		}
+
+		fieldValue := v.Field(i)
+
+		if isNestedStructType(fieldValue.Type()) {
+			for fieldValue.Kind() == reflect.Pointer {
+				if fieldValue.IsNil() {
+					fieldValue.Set(reflect.New(fieldValue.Type().Elem()))
+				}
+				fieldValue = fieldValue.Elem()
+			}
+
+			childPrefix := fullName
+			if structField.Anonymous {
+				childPrefix = prefix
+			}
+			if err := bindStruct(parser, fieldValue, childPrefix); err != nil {
+				return err
+			}
+			continue
+		}
+
+		fieldType := fieldValue.Type()
+
+		opts := options{description: structField.Tag.Get("desc")}
+		for _, flag := range tagParts[1:] {
+			switch strings.TrimSpace(flag) {
+			case "required":
+				opts.required = true
+			case "nonempty":
+				opts.nonEmpty = true
+			case "skipempty":
+				opts.skipEmpty = true
+			}
+		}
+
+		if def, ok := structField.Tag.Lookup("default"); ok {
+			scratch := reflect.New(fieldType).Elem()
+			if err := parse(scratch, def, true); err != nil {
+				return fmt.Errorf("conf: Bind: field %s: invalid default: %w", fullName, err)
+			}
+			opts.fallback = scratch.Interface()
+		}
+
+		parser.mu.Lock()
+		parser.fields[fullName] = &field{value: reflectValue{fieldValue}, opts: opts}
+		parser.mu.Unlock()
+	}
+
+	return nil
+}
+
+// isNestedStructType reports whether t, possibly through any number of pointer
+// indirections, is a struct that Bind should recurse into rather than register as a
+// single leaf field via parse.
+func isNestedStructType(t reflect.Type) bool {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return false
+	}
+	return !reflect.PointerTo(t).Implements(textUnmarshalerType) && !reflect.PointerTo(t).Implements(binaryUnmarshalerType)
+}
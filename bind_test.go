@@ -0,0 +1,80 @@
+package conf_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/davidmdm/conf"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBind(t *testing.T) {
+	type DB struct {
+		URL string `conf:"URL,required"`
+	}
+
+	type Embedded struct {
+		Region string `conf:"REGION"`
+	}
+
+	type Config struct {
+		Embedded
+		DB      DB
+		Timeout time.Duration `conf:"TIMEOUT" default:"5s"`
+		Debug   bool
+		ignored string
+		Skipped string `conf:"-"`
+	}
+
+	values := map[string]string{
+		"URL":    "ignored-without-prefix",
+		"DB_URL": "postgres://localhost/db",
+		"REGION": "us-east-1",
+		"DEBUG":  "true",
+	}
+
+	parser := conf.MakeParser(conf.FromLookup(func(name string) (string, bool) {
+		value, ok := values[name]
+		return value, ok
+	}))
+
+	var config Config
+	require.NoError(t, conf.Bind(parser, &config))
+	require.NoError(t, parser.Parse())
+
+	require.Equal(t, "postgres://localhost/db", config.DB.URL)
+	require.Equal(t, "us-east-1", config.Region)
+	require.Equal(t, 5*time.Second, config.Timeout)
+	require.True(t, config.Debug)
+	require.Empty(t, config.ignored)
+	require.Empty(t, config.Skipped)
+}
+
+func TestBindRequiresStructPointer(t *testing.T) {
+	parser := conf.MakeParser(conf.FromLookup(func(string) (string, bool) { return "", false }))
+
+	var notAStruct string
+	require.EqualError(
+		t,
+		conf.Bind(parser, &notAStruct),
+		"conf: Bind: v must be a non-nil pointer to a struct, got *string",
+	)
+
+	require.EqualError(
+		t,
+		conf.Bind(parser, struct{}{}),
+		"conf: Bind: v must be a non-nil pointer to a struct, got struct {}",
+	)
+}
+
+func TestBindRequired(t *testing.T) {
+	parser := conf.MakeParser(conf.FromLookup(func(string) (string, bool) { return "", false }))
+
+	type Config struct {
+		DatabaseURL string `conf:"DATABASE_URL,required"`
+	}
+
+	var config Config
+	require.NoError(t, conf.Bind(parser, &config))
+	require.EqualError(t, parser.Parse(), "failed to parse variable(s): DATABASE_URL: field is required")
+}
@@ -1,3 +1,6 @@
+// Package conf parses configuration into Go values from one or more lookup sources (the
+// environment, command-line flags, files, structured config files, or a remote store), with
+// optional live-reload via Watch. See CHANGELOG.md for breaking changes between versions.
 package conf
 
 import (
@@ -5,7 +8,9 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"reflect"
 	"strings"
+	"sync"
 
 	"github.com/davidmdm/x/xerr"
 )
@@ -13,22 +18,72 @@ import (
 type LookupFunc func(string) (string, bool)
 
 type field struct {
-	value value
-	opts  options
+	mu       sync.RWMutex
+	value    value
+	opts     options
+	onChange []func(old, new any)
 }
 
 type Parser struct {
-	fields map[string]field
-	lookup LookupFunc
+	mu             *sync.Mutex
+	fields         map[string]*field
+	lookup         LookupFunc
+	watchables     []WatchableLookupFunc
+	helpRequesters []HelpRequester
 }
 
-func MakeParser(funcs ...LookupFunc) Parser {
-	lookupFuncs := make([]LookupFunc, 0, len(funcs))
-	for _, fn := range funcs {
-		if fn == nil {
+// Source is a lookup source to register with MakeParser. Build one with FromLookup,
+// FromWatchable, or FromHelpRequester.
+type Source struct {
+	lookup    LookupFunc
+	watchable WatchableLookupFunc
+	help      HelpRequester
+}
+
+// FromLookup wraps a plain LookupFunc as a Source.
+func FromLookup(fn LookupFunc) Source {
+	return Source{lookup: fn}
+}
+
+// FromWatchable wraps a WatchableLookupFunc, such as the one returned by
+// WatchableFileSystem, WatchableStructuredFile, or WatchableEnviron, as a Source. MakeParser
+// remembers it separately so Watch can subscribe to it. A nil w yields a zero Source, which
+// MakeParser ignores.
+func FromWatchable(w WatchableLookupFunc) Source {
+	if w == nil {
+		return Source{}
+	}
+	return Source{lookup: w.Lookup, watchable: w}
+}
+
+// FromHelpRequester wraps a HelpRequester, such as the one returned by CommandLineArgs, as a
+// Source. MakeParser remembers it separately so Parse can react to -h/--help. A nil h yields
+// a zero Source, which MakeParser ignores.
+func FromHelpRequester(h HelpRequester) Source {
+	if h == nil {
+		return Source{}
+	}
+	return Source{lookup: h.Lookup, help: h}
+}
+
+// MakeParser builds a Parser from zero or more Sources, consulted in order for every field.
+// With no sources, os.LookupEnv is used.
+func MakeParser(sources ...Source) Parser {
+	lookupFuncs := make([]LookupFunc, 0, len(sources))
+	var watchables []WatchableLookupFunc
+	var helpRequesters []HelpRequester
+
+	for _, source := range sources {
+		if source.lookup == nil {
 			continue
 		}
-		lookupFuncs = append(lookupFuncs, fn)
+		lookupFuncs = append(lookupFuncs, source.lookup)
+		if source.watchable != nil {
+			watchables = append(watchables, source.watchable)
+		}
+		if source.help != nil {
+			helpRequesters = append(helpRequesters, source.help)
+		}
 	}
 
 	lookup := os.LookupEnv
@@ -37,14 +92,38 @@ func MakeParser(funcs ...LookupFunc) Parser {
 	}
 
 	return Parser{
-		fields: make(map[string]field),
-		lookup: lookup,
+		mu:             &sync.Mutex{},
+		fields:         make(map[string]*field),
+		lookup:         lookup,
+		watchables:     watchables,
+		helpRequesters: helpRequesters,
 	}
 }
 
 func (parser Parser) Parse() error {
-	errs := make([]error, 0, len(parser.fields))
-	for name, field := range parser.fields {
+	for _, requester := range parser.helpRequesters {
+		if requester.HelpRequested() {
+			parser.Usage(os.Stdout)
+			return ErrHelpRequested
+		}
+	}
+
+	parser.mu.Lock()
+	names := make([]string, 0, len(parser.fields))
+	fields := make([]*field, 0, len(parser.fields))
+	for name, f := range parser.fields {
+		names = append(names, name)
+		fields = append(fields, f)
+	}
+	parser.mu.Unlock()
+
+	errs := make([]error, 0, len(fields))
+	for i, name := range names {
+		field := fields[i]
+
+		var old, updated any
+		changed := false
+
 		if err := func() (err error) {
 			defer func() {
 				if recovered := recover(); recovered != nil {
@@ -62,19 +141,60 @@ func (parser Parser) Parse() error {
 				}
 			}
 
+			field.mu.Lock()
+			defer field.mu.Unlock()
+
+			old = field.value.Get()
+
 			if !ok {
 				if field.opts.required {
 					return errors.New("field is required")
 				}
 				if field.opts.fallback != nil {
+					for _, validate := range field.opts.validators {
+						if err := validate(field.opts.fallback); err != nil {
+							return err
+						}
+					}
 					field.value.Set(field.opts.fallback)
 				}
-				return nil
+			} else {
+				// Parse into a scratch value and only commit it to field.value once every
+				// validator has accepted it, so a rejected value never reaches the bound
+				// variable - important for Watch, where the previous good value must survive
+				// a failed reload instead of being left corrupted while Parse reports the error.
+				scratch := reflect.New(field.value.Type()).Elem()
+				if err := parse(scratch, text, true); err != nil {
+					return err
+				}
+
+				parsed := scratch.Interface()
+				for _, validate := range field.opts.validators {
+					if err := validate(parsed); err != nil {
+						return err
+					}
+				}
+
+				field.value.Set(parsed)
 			}
 
-			return field.value.Parse(text)
+			updated = field.value.Get()
+			changed = !reflect.DeepEqual(old, updated)
+
+			return nil
 		}(); err != nil {
 			errs = append(errs, fmt.Errorf("%s: %w", name, err))
+			continue
+		}
+
+		if changed {
+			field.mu.RLock()
+			callbacks := append([]func(old, new any){}, field.onChange...)
+			field.mu.RUnlock()
+
+			for _, callback := range callbacks {
+				callback(old, updated)
+			}
 		}
 	}
 
@@ -88,55 +208,122 @@ func (parser Parser) MustParse() {
 	}
 }
 
+// Var registers p with parser under name. Parse and, if parser.Watch is running, every
+// reload it triggers write directly into *p while holding the field's internal lock; that
+// lock is not exported, so p itself is not safe to read concurrently with Watch. Once
+// Watch has been started, read the field's value with Get or OnChange instead of p - only
+// their values are synchronized with the background reloads.
 func Var[T any](parser Parser, p *T, name string, opts ...Option[T]) {
 	var options options
 	for _, apply := range opts {
 		apply(&options)
 	}
-	parser.fields[name] = field{
+
+	parser.mu.Lock()
+	defer parser.mu.Unlock()
+
+	parser.fields[name] = &field{
 		value: genericValue[T]{p},
 		opts:  options,
 	}
 }
 
-// CommandLineArgs returns a lookup function that will search the provided args for flags.
+// Get returns the current value of the field registered as name, acquiring the field's
+// internal lock for the read. Unlike reading the pointer passed to Var, this is safe to
+// call concurrently with Parse, including a reload triggered by Watch. name must already be
+// registered with Var.
+func Get[T any](parser Parser, name string) T {
+	parser.mu.Lock()
+	f, ok := parser.fields[name]
+	parser.mu.Unlock()
+
+	if !ok {
+		panic(fmt.Sprintf("conf: Get: no field registered for %q, call Var first", name))
+	}
+
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	return f.value.Get().(T)
+}
+
+// OnChange registers fn to run whenever Parse, including one triggered by Watch, parses a
+// new value for name that differs from the previous one. name must already be registered
+// with Var. Callbacks run synchronously from within Parse, after the field's new value has
+// been committed, in the order they were registered.
+func OnChange[T any](parser Parser, name string, fn func(old, new T)) {
+	parser.mu.Lock()
+	f, ok := parser.fields[name]
+	parser.mu.Unlock()
+
+	if !ok {
+		panic(fmt.Sprintf("conf: OnChange: no field registered for %q, call Var first", name))
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.onChange = append(f.onChange, func(old, new any) {
+		fn(old.(T), new.(T))
+	})
+}
+
+// commandLineArgsSource is the lookup source returned by CommandLineArgs. It implements
+// HelpRequester so that MakeParser can recognize -h/--help.
+type commandLineArgsSource struct {
+	values map[string][]string
+	help   bool
+}
+
+func (c *commandLineArgsSource) Lookup(name string) (string, bool) {
+	name = strings.ReplaceAll(strings.ToLower(name), "_", "-")
+	value, ok := c.values[name]
+	return strings.Join(value, ","), ok
+}
+
+func (c *commandLineArgsSource) HelpRequested() bool {
+	return c.help
+}
+
+// CommandLineArgs returns a lookup source that will search the provided args for flags.
 // Since we often want our EnvironmentVariable name declarations to be reusable for command line args
 // the lookup is case-insensitive and all underscores are changes to dashes.
 // For example, a variable mapped to DATABASE_URL can be found using the --database-url flag when working with CommandLineArgs.
-func CommandLineArgs(args ...string) LookupFunc {
+//
+// If args contains -h or --help, the returned source reports it via HelpRequested, which
+// MakeParser wires up so that Parse prints usage and returns ErrHelpRequested.
+func CommandLineArgs(args ...string) HelpRequester {
 	if len(args) == 0 {
 		args = os.Args[1:]
 	}
 
-	var (
-		m    = map[string][]string{}
-		flag = ""
-	)
+	source := &commandLineArgsSource{values: map[string][]string{}}
 
+	flag := ""
 	for _, arg := range args {
 		switch {
+		case arg == "-h" || arg == "--help":
+			if flag != "" && len(source.values[flag]) == 0 {
+				source.values[flag] = []string{"true"}
+			}
+			source.help = true
+			flag = ""
 		case strings.HasPrefix(arg, "-"):
-			if flag != "" && len(m[flag]) == 0 {
-				m[flag] = []string{"true"}
+			if flag != "" && len(source.values[flag]) == 0 {
+				source.values[flag] = []string{"true"}
 			}
 			flag = strings.ToLower(strings.TrimLeft(arg, "-"))
 			if key, value, ok := strings.Cut(flag, "="); ok {
-				m[key] = append(m[key], value)
+				source.values[key] = append(source.values[key], value)
 				flag = ""
 			}
 		case flag == "":
 			// skip positional args
 		default:
-			m[flag] = append(m[flag], arg)
+			source.values[flag] = append(source.values[flag], arg)
 			flag = ""
 		}
 	}
 
-	return func(name string) (string, bool) {
-		name = strings.ReplaceAll(strings.ToLower(name), "_", "-")
-		value, ok := m[name]
-		return strings.Join(value, ","), ok
-	}
+	return source
 }
 
 type FileSystemOptions struct {
@@ -176,4 +363,4 @@ func joinLookupFuncs(fns ...LookupFunc) LookupFunc {
 	}
 }
 
-var Environ = MakeParser(os.LookupEnv)
+var Environ = MakeParser(FromLookup(os.LookupEnv))
@@ -34,7 +34,7 @@ func TestVar(t *testing.T) {
 		mapint      map[string]int
 	}
 
-	environment := conf.MakeParser(func() conf.LookupFunc {
+	environment := conf.MakeParser(conf.FromLookup(func() conf.LookupFunc {
 		e := map[string]string{
 			"i":      "-1",
 			"ui":     "1",
@@ -50,7 +50,7 @@ func TestVar(t *testing.T) {
 			value, ok := e[s]
 			return value, ok
 		}
-	}())
+	}()))
 
 	conf.Var(environment, &config.int, "i")
 	conf.Var(environment, &config.uint, "ui")
@@ -76,7 +76,7 @@ func TestVar(t *testing.T) {
 }
 
 func TestOptions(t *testing.T) {
-	parser := conf.MakeParser(func() conf.LookupFunc {
+	parser := conf.MakeParser(conf.FromLookup(func() conf.LookupFunc {
 		m := map[string]string{
 			"nonEmpty":  "",
 			"skipEmpty": "",
@@ -85,7 +85,7 @@ func TestOptions(t *testing.T) {
 			value, ok := m[s]
 			return value, ok
 		}
-	}())
+	}()))
 
 	var required string
 	var nonempty string
@@ -106,9 +106,9 @@ func TestOptions(t *testing.T) {
 }
 
 func TestMapParsingErrors(t *testing.T) {
-	e1 := conf.MakeParser(func(name string) (string, bool) {
+	e1 := conf.MakeParser(conf.FromLookup(func(name string) (string, bool) {
 		return "3=4", true
-	})
+	}))
 
 	var boolint map[bool]int
 	conf.Var(e1, &boolint, "BOOLINT")
@@ -125,12 +125,12 @@ func TestMapParsingErrors(t *testing.T) {
 }
 
 func TestParsePanicRecovery(t *testing.T) {
-	parser := conf.MakeParser(func(s string) (string, bool) {
+	parser := conf.MakeParser(conf.FromLookup(func(s string) (string, bool) {
 		if s != strings.ToUpper(s) {
 			panic("lookup key not capitalized")
 		}
 		return s, true
-	})
+	}))
 
 	var x string
 
@@ -157,7 +157,7 @@ func (text *CapText) UnmarshalText(data []byte) error {
 func TestTextUnmarshaler(t *testing.T) {
 	var text CapText
 
-	environment := conf.MakeParser(func(s string) (string, bool) { return "value", true })
+	environment := conf.MakeParser(conf.FromLookup(func(s string) (string, bool) { return "value", true }))
 	conf.Var(environment, &text, "VAR")
 
 	require.NoError(t, environment.Parse())
@@ -180,7 +180,7 @@ func (text *Base64Text) UnmarshalBinary(data []byte) error {
 func TestBinaryUnmarshaler(t *testing.T) {
 	var text Base64Text
 
-	environment := conf.MakeParser(func(s string) (string, bool) { return "aGVsbG8gd29ybGQK", true })
+	environment := conf.MakeParser(conf.FromLookup(func(s string) (string, bool) { return "aGVsbG8gd29ybGQK", true }))
 	conf.Var(environment, &text, "VAR")
 
 	require.NoError(t, environment.Parse())
@@ -189,7 +189,7 @@ func TestBinaryUnmarshaler(t *testing.T) {
 
 func TestCommandLineArgsSource(t *testing.T) {
 	environment := conf.MakeParser(
-		conf.CommandLineArgs("--database-url", "db", "-force", "--filter=*.sql", "-count", "42", "-input", "a", "--input", "b"),
+		conf.FromHelpRequester(conf.CommandLineArgs("--database-url", "db", "-force", "--filter=*.sql", "-count", "42", "-input", "a", "--input", "b")),
 	)
 
 	var (
@@ -220,14 +220,14 @@ func TestCommandLineArgsSource(t *testing.T) {
 
 func TestMultipleLookups(t *testing.T) {
 	environment := conf.MakeParser(
-		conf.CommandLineArgs("--max=42"),
-		func(name string) (string, bool) {
+		conf.FromHelpRequester(conf.CommandLineArgs("--max=42")),
+		conf.FromLookup(func(name string) (string, bool) {
 			value, ok := map[string]string{
 				"MAX":  "10",
 				"NAME": "bob",
 			}[name]
 			return value, ok
-		},
+		}),
 	)
 
 	var (
@@ -245,12 +245,11 @@ func TestMultipleLookups(t *testing.T) {
 }
 
 func TestFileSystem(t *testing.T) {
-	require.NoError(t, os.RemoveAll("./test_output"))
-	require.NoError(t, os.MkdirAll("./test_output", 0o755))
+	dir := t.TempDir()
 
-	require.NoError(t, os.WriteFile("./test_output/secret.txt", []byte("hello world"), 0o644))
+	require.NoError(t, os.WriteFile(dir+"/secret.txt", []byte("hello world"), 0o644))
 
-	fs := conf.MakeParser(conf.FileSystem(conf.FileSystemOptions{Base: "./test_output"}))
+	fs := conf.MakeParser(conf.FromLookup(conf.FileSystem(conf.FileSystemOptions{Base: dir})))
 
 	var secret string
 	conf.Var(fs, &secret, "secret.txt")
@@ -261,7 +260,7 @@ func TestFileSystem(t *testing.T) {
 
 func TestInvalidDestination(t *testing.T) {
 	var invalid struct{ string }
-	parser := conf.MakeParser(func(s string) (string, bool) { return "VALUE", true })
+	parser := conf.MakeParser(conf.FromLookup(func(s string) (string, bool) { return "VALUE", true }))
 
 	conf.Var(parser, &invalid, "NAME")
 
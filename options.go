@@ -1,10 +1,16 @@
 package conf
 
+import "fmt"
+
 type options struct {
-	required  bool
-	nonEmpty  bool
-	skipEmpty bool
-	fallback  any
+	required    bool
+	nonEmpty    bool
+	skipEmpty   bool
+	fallback    any
+	description string
+	placeholder string
+	allowed     []string
+	validators  []func(any) error
 }
 
 type Option[T any] func(*options)
@@ -32,3 +38,50 @@ func Default[T any](value T) Option[T] {
 		o.fallback = value
 	}
 }
+
+// Description sets the text shown next to this field in Parser.Usage.
+func Description[T any](value string) Option[T] {
+	return func(o *options) {
+		o.description = value
+	}
+}
+
+// Placeholder overrides the value shown in place of the field's type in Parser.Usage, e.g.
+// "DSN" for a field that would otherwise show as "string".
+func Placeholder[T any](value string) Option[T] {
+	return func(o *options) {
+		o.placeholder = value
+	}
+}
+
+// OneOf restricts a field to one of the given values, validated after Parse successfully
+// parses it. The allowed set is also shown in Parser.Usage.
+func OneOf[T comparable](values ...T) Option[T] {
+	allowed := make([]string, len(values))
+	for i, value := range values {
+		allowed[i] = fmt.Sprint(value)
+	}
+
+	return func(o *options) {
+		o.allowed = append(o.allowed, allowed...)
+		o.validators = append(o.validators, func(value any) error {
+			v := value.(T)
+			for _, candidate := range values {
+				if candidate == v {
+					return nil
+				}
+			}
+			return fmt.Errorf("value %q not in allowed set %v", fmt.Sprint(v), allowed)
+		})
+	}
+}
+
+// Validate runs fn against a field's value after Parse successfully parses it, failing
+// Parse with fn's error if it returns one.
+func Validate[T any](fn func(T) error) Option[T] {
+	return func(o *options) {
+		o.validators = append(o.validators, func(value any) error {
+			return fn(value.(T))
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package remote
+
+import (
+	"context"
+	"time"
+
+	"github.com/davidmdm/conf"
+	"github.com/hashicorp/consul/api"
+)
+
+type consulSource struct {
+	subscribers
+	kv     *api.KV
+	prefix string
+	cancel context.CancelFunc
+}
+
+func (c *consulSource) Lookup(name string) (string, bool) {
+	pair, _, err := c.kv.Get(toKey(c.prefix, name), nil)
+	if err != nil {
+		panic(err)
+	}
+	if pair == nil {
+		return "", false
+	}
+	return string(pair.Value), true
+}
+
+// Consul returns a conf.WatchableLookupFunc that resolves a variable name to a key under
+// prefix in Consul's KV store: a name like DATABASE_URL maps to the key
+// prefix+"/database/url". It watches prefix via a blocking query so that a change in
+// Consul triggers a conf.Parser.Watch reload.
+func Consul(client *api.Client, prefix string) conf.WatchableLookupFunc {
+	ctx, cancel := context.WithCancel(context.Background())
+	source := &consulSource{kv: client.KV(), prefix: prefix, cancel: cancel}
+	go source.watch(ctx)
+	return source
+}
+
+// Close stops the blocking query loop started by Consul. Parser.Watch calls this once its
+// context is done, so that watching Consul doesn't leak its goroutine past Watch's lifetime.
+func (c *consulSource) Close() {
+	c.cancel()
+}
+
+func (c *consulSource) watch(ctx context.Context) {
+	var lastIndex uint64
+	for {
+		_, meta, err := c.kv.List(c.prefix, (&api.QueryOptions{WaitIndex: lastIndex}).WithContext(ctx))
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			select {
+			case <-time.After(time.Second):
+			case <-ctx.Done():
+				return
+			}
+			continue
+		}
+
+		if lastIndex != 0 && meta.LastIndex != lastIndex {
+			c.notify()
+		}
+		lastIndex = meta.LastIndex
+	}
+}
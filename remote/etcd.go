@@ -0,0 +1,57 @@
+package remote
+
+import (
+	"context"
+	"time"
+
+	"github.com/davidmdm/conf"
+	clientv3 "go.etcd.io/etcd/client/v3"
+)
+
+type etcdSource struct {
+	subscribers
+	client *clientv3.Client
+	prefix string
+	cancel context.CancelFunc
+}
+
+func (e *etcdSource) Lookup(name string) (string, bool) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	resp, err := e.client.Get(ctx, toKey(e.prefix, name))
+	if err != nil {
+		panic(err)
+	}
+	if len(resp.Kvs) == 0 {
+		return "", false
+	}
+	return string(resp.Kvs[0].Value), true
+}
+
+// Etcd returns a conf.WatchableLookupFunc that resolves a variable name to a key under
+// prefix in etcd: a name like DATABASE_URL maps to the key prefix+"/database/url". It
+// watches prefix so that a change in etcd triggers a conf.Parser.Watch reload.
+func Etcd(client *clientv3.Client, prefix string) conf.WatchableLookupFunc {
+	ctx, cancel := context.WithCancel(context.Background())
+	source := &etcdSource{client: client, prefix: prefix, cancel: cancel}
+	go source.watch(ctx)
+	return source
+}
+
+// Close stops the watch loop started by Etcd. Parser.Watch calls this once its context is
+// done, so that watching etcd doesn't leak its goroutine past Watch's lifetime.
+func (e *etcdSource) Close() {
+	e.cancel()
+}
+
+func (e *etcdSource) watch(ctx context.Context) {
+	for resp := range e.client.Watch(ctx, e.prefix, clientv3.WithPrefix()) {
+		if resp.Err() != nil {
+			continue
+		}
+		if len(resp.Events) > 0 {
+			e.notify()
+		}
+	}
+}
@@ -0,0 +1,42 @@
+// Package remote provides conf lookup sources backed by remote key/value stores (Consul,
+// etcd). It is kept as a separate module from github.com/davidmdm/conf so that pulling in
+// a remote backend's heavy client dependencies is opt-in.
+package remote
+
+import (
+	"strings"
+	"sync"
+)
+
+// subscribers is embedded by the backends in this package to manage their list of Watch
+// change callbacks.
+type subscribers struct {
+	mu   sync.Mutex
+	subs []func()
+}
+
+func (s *subscribers) Subscribe(onChange func()) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.subs = append(s.subs, onChange)
+}
+
+func (s *subscribers) notify() {
+	s.mu.Lock()
+	subs := append([]func(){}, s.subs...)
+	s.mu.Unlock()
+
+	for _, sub := range subs {
+		sub()
+	}
+}
+
+// toKey translates a variable name such as DATABASE_URL into a key under prefix, e.g.
+// prefix/database/url.
+func toKey(prefix, name string) string {
+	key := strings.ToLower(strings.ReplaceAll(name, "_", "/"))
+	if prefix == "" {
+		return key
+	}
+	return prefix + "/" + key
+}
@@ -0,0 +1,212 @@
+package conf
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// StructuredFileOptions configures StructuredFile.
+type StructuredFileOptions struct {
+	// Base is the directory that a relative path is resolved against. Defaults to ".".
+	Base string
+	// Decode unmarshals the file's contents into v, typically a *any. Required.
+	Decode func(data []byte, v any) error
+}
+
+// StructuredFile returns a LookupFunc that reads and decodes the document at path once,
+// and resolves a variable name to a value inside that document by walking it as a
+// dotted/underscored path. For example DATABASE_URL resolves to database.url and
+// SERVER_LISTEN_ADDR resolves to server.listen_addr.
+//
+// Scalars found in the document are coerced back to their string form so the existing
+// parse() machinery in value.go can still apply its usual typing rules. A flat slice or
+// map of scalars is rendered using the same syntax Parse already understands (comma
+// separated items, or comma separated key=value pairs). Anything else - nested slices,
+// nested maps, or arbitrary documents - is re-encoded as JSON so that a destination type
+// implementing encoding.TextUnmarshaler can decode it directly.
+func StructuredFile(path string, opts StructuredFileOptions) LookupFunc {
+	if opts.Base == "" {
+		opts.Base = "."
+	}
+	if opts.Decode == nil {
+		panic("conf: StructuredFile requires a Decode function")
+	}
+
+	return func(name string) (string, bool) {
+		fullPath := path
+		if !filepath.IsAbs(fullPath) {
+			fullPath = filepath.Join(opts.Base, fullPath)
+		}
+
+		data, err := os.ReadFile(fullPath)
+		if err != nil {
+			if !errors.Is(err, os.ErrNotExist) {
+				panic(err)
+			}
+			return "", false
+		}
+
+		var doc any
+		if err := opts.Decode(data, &doc); err != nil {
+			panic(fmt.Errorf("failed to decode %s: %w", fullPath, err))
+		}
+
+		value, ok := lookupPath(doc, name)
+		if !ok {
+			return "", false
+		}
+
+		return encodeValue(value), true
+	}
+}
+
+// YAMLFile is a StructuredFile that decodes path as YAML.
+func YAMLFile(path string, opts StructuredFileOptions) LookupFunc {
+	opts.Decode = yamlDecode
+	return StructuredFile(path, opts)
+}
+
+// JSONFile is a StructuredFile that decodes path as JSON.
+func JSONFile(path string, opts StructuredFileOptions) LookupFunc {
+	opts.Decode = json.Unmarshal
+	return StructuredFile(path, opts)
+}
+
+// TOMLFile is a StructuredFile that decodes path as TOML.
+func TOMLFile(path string, opts StructuredFileOptions) LookupFunc {
+	opts.Decode = tomlDecode
+	return StructuredFile(path, opts)
+}
+
+func yamlDecode(data []byte, v any) error {
+	return yaml.Unmarshal(data, v)
+}
+
+func tomlDecode(data []byte, v any) error {
+	_, err := toml.Decode(string(data), v)
+	return err
+}
+
+// lookupPath walks doc looking for name, a variable name such as SERVER_LISTEN_ADDR.
+// Maps are matched by greedily joining the longest run of remaining tokens with "_" and
+// backtracking on failure, so that both DATABASE_URL (-> database.url) and
+// SERVER_LISTEN_ADDR (-> server.listen_addr) resolve against snake_cased document keys.
+// Slices are indexed by a single numeric token.
+func lookupPath(doc any, name string) (any, bool) {
+	tokens := strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+		return r == '_' || r == '.'
+	})
+	return walk(doc, tokens)
+}
+
+func walk(doc any, tokens []string) (any, bool) {
+	if len(tokens) == 0 {
+		return doc, true
+	}
+
+	switch node := doc.(type) {
+	case map[string]any:
+		for i := len(tokens); i >= 1; i-- {
+			key := strings.Join(tokens[:i], "_")
+			child, ok := node[key]
+			if !ok {
+				continue
+			}
+			if value, ok := walk(child, tokens[i:]); ok {
+				return value, true
+			}
+		}
+		return nil, false
+
+	case []any:
+		index, err := strconv.Atoi(tokens[0])
+		if err != nil || index < 0 || index >= len(node) {
+			return nil, false
+		}
+		return walk(node[index], tokens[1:])
+
+	default:
+		return nil, false
+	}
+}
+
+func isScalar(v any) bool {
+	switch v.(type) {
+	case nil, string, bool, int, int64, float64, time.Time:
+		return true
+	default:
+		return false
+	}
+}
+
+func formatScalar(v any) string {
+	switch value := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return value
+	case bool:
+		return strconv.FormatBool(value)
+	case int:
+		return strconv.Itoa(value)
+	case int64:
+		return strconv.FormatInt(value, 10)
+	case float64:
+		return strconv.FormatFloat(value, 'f', -1, 64)
+	case time.Time:
+		return value.Format(time.RFC3339Nano)
+	default:
+		return fmt.Sprint(value)
+	}
+}
+
+// encodeValue renders a decoded document value back to the text form Parse expects: a
+// flat slice or map of scalars uses the comma separated syntax value.go already parses,
+// and everything else falls back to JSON so a encoding.TextUnmarshaler destination can
+// decode it directly.
+func encodeValue(v any) string {
+	switch node := v.(type) {
+	case []any:
+		parts := make([]string, len(node))
+		for i, elem := range node {
+			if !isScalar(elem) {
+				return mustMarshalJSON(v)
+			}
+			parts[i] = formatScalar(elem)
+		}
+		return strings.Join(parts, ",")
+
+	case map[string]any:
+		parts := make([]string, 0, len(node))
+		for key, elem := range node {
+			if !isScalar(elem) {
+				return mustMarshalJSON(v)
+			}
+			parts = append(parts, key+"="+formatScalar(elem))
+		}
+		return strings.Join(parts, ",")
+
+	default:
+		if isScalar(v) {
+			return formatScalar(v)
+		}
+		return mustMarshalJSON(v)
+	}
+}
+
+func mustMarshalJSON(v any) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		panic(err)
+	}
+	return string(data)
+}
@@ -0,0 +1,85 @@
+package conf_test
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/davidmdm/conf"
+	"github.com/stretchr/testify/require"
+)
+
+func TestYAMLFile(t *testing.T) {
+	dir := t.TempDir()
+
+	require.NoError(t, os.WriteFile(dir+"/config.yaml", []byte(`
+database:
+  url: postgres://localhost/db
+server:
+  listen_addr: 0.0.0.0:8080
+  timeout: 5s
+tags:
+  - a
+  - b
+limits:
+  x: 1
+  y: 2
+`), 0o644))
+
+	parser := conf.MakeParser(conf.FromLookup(conf.YAMLFile("config.yaml", conf.StructuredFileOptions{Base: dir})))
+
+	var (
+		databaseURL string
+		listenAddr  string
+		timeout     time.Duration
+		tags        []string
+		limits      map[string]int
+	)
+
+	conf.Var(parser, &databaseURL, "DATABASE_URL")
+	conf.Var(parser, &listenAddr, "SERVER_LISTEN_ADDR")
+	conf.Var(parser, &timeout, "SERVER_TIMEOUT")
+	conf.Var(parser, &tags, "TAGS")
+	conf.Var(parser, &limits, "LIMITS")
+
+	require.NoError(t, parser.Parse())
+
+	require.Equal(t, "postgres://localhost/db", databaseURL)
+	require.Equal(t, "0.0.0.0:8080", listenAddr)
+	require.Equal(t, 5*time.Second, timeout)
+	require.Equal(t, []string{"a", "b"}, tags)
+	require.Equal(t, map[string]int{"x": 1, "y": 2}, limits)
+}
+
+type Server struct {
+	Host string
+}
+
+func TestJSONFileNested(t *testing.T) {
+	dir := t.TempDir()
+
+	require.NoError(t, os.WriteFile(dir+"/config.json", []byte(`{
+		"servers": [
+			{"Host": "a"},
+			{"Host": "b"}
+		]
+	}`), 0o644))
+
+	parser := conf.MakeParser(conf.FromLookup(conf.JSONFile("config.json", conf.StructuredFileOptions{Base: dir})))
+
+	var servers []Server
+	conf.Var(parser, &servers, "SERVERS")
+
+	require.NoError(t, parser.Parse())
+	require.Equal(t, []Server{{Host: "a"}, {Host: "b"}}, servers)
+}
+
+func TestTOMLFileMissing(t *testing.T) {
+	parser := conf.MakeParser(conf.FromLookup(conf.TOMLFile("does-not-exist.toml", conf.StructuredFileOptions{Base: t.TempDir()})))
+
+	var value string
+	conf.Var(parser, &value, "ANYTHING")
+
+	require.NoError(t, parser.Parse())
+	require.Equal(t, "", value)
+}
@@ -0,0 +1,76 @@
+package conf
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+)
+
+// ErrHelpRequested is returned by Parse when a registered HelpRequester, such as the one
+// CommandLineArgs returns, reports that -h or --help was passed. Parse prints usage to
+// os.Stdout before returning it, and skips its usual required-field validation.
+var ErrHelpRequested = errors.New("conf: help requested")
+
+// HelpRequester is implemented by lookup sources, such as the one returned by
+// CommandLineArgs, that can themselves detect a request for usage/help while reading their
+// arguments. Wrap one with FromHelpRequester and pass it to MakeParser to wire it up to Parse.
+type HelpRequester interface {
+	Lookup(name string) (string, bool)
+	HelpRequested() bool
+}
+
+// Usage writes a table describing every field registered with Var to w: its flag spelling
+// (as understood by CommandLineArgs), its environment variable name, its type, whether it
+// is required, its default, and its description.
+func (parser Parser) Usage(w io.Writer) {
+	parser.mu.Lock()
+	names := make([]string, 0, len(parser.fields))
+	fields := make(map[string]*field, len(parser.fields))
+	for name, f := range parser.fields {
+		names = append(names, name)
+		fields[name] = f
+	}
+	parser.mu.Unlock()
+
+	sort.Strings(names)
+
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "FLAG\tENV\tTYPE\tREQUIRED\tDEFAULT\tALLOWED\tDESCRIPTION")
+
+	for _, name := range names {
+		f := fields[name]
+
+		f.mu.RLock()
+		opts := f.opts
+		typ := f.value.Type().String()
+		f.mu.RUnlock()
+
+		placeholder := opts.placeholder
+		if placeholder == "" {
+			placeholder = typ
+		}
+		flag := fmt.Sprintf("--%s <%s>", strings.ReplaceAll(strings.ToLower(name), "_", "-"), placeholder)
+
+		required := ""
+		if opts.required {
+			required = "true"
+		}
+
+		def := ""
+		if opts.fallback != nil {
+			def = fmt.Sprint(opts.fallback)
+		}
+
+		allowed := ""
+		if len(opts.allowed) > 0 {
+			allowed = fmt.Sprintf("%v", opts.allowed)
+		}
+
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n", flag, name, typ, required, def, allowed, opts.description)
+	}
+
+	tw.Flush()
+}
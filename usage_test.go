@@ -0,0 +1,75 @@
+package conf_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/davidmdm/conf"
+	"github.com/stretchr/testify/require"
+)
+
+// captureStdout temporarily redirects os.Stdout into a buffer for the duration of fn.
+func captureStdout(t *testing.T, fn func()) string {
+	t.Helper()
+
+	r, w, err := os.Pipe()
+	require.NoError(t, err)
+
+	original := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = original }()
+
+	fn()
+
+	require.NoError(t, w.Close())
+	data, err := io.ReadAll(r)
+	require.NoError(t, err)
+
+	return string(data)
+}
+
+func TestCommandLineArgsHelp(t *testing.T) {
+	parser := conf.MakeParser(conf.FromHelpRequester(conf.CommandLineArgs("--help")))
+
+	var databaseURL string
+	conf.Var(parser, &databaseURL, "DATABASE_URL", conf.Required[string](true))
+
+	var err error
+	output := captureStdout(t, func() { err = parser.Parse() })
+
+	require.True(t, errors.Is(err, conf.ErrHelpRequested))
+	require.Contains(t, output, "DATABASE_URL")
+	require.Contains(t, output, "--database-url")
+}
+
+func TestUsage(t *testing.T) {
+	parser := conf.MakeParser(conf.FromHelpRequester(conf.CommandLineArgs()))
+
+	var timeout time.Duration
+	conf.Var(
+		parser,
+		&timeout,
+		"TIMEOUT",
+		conf.Default(5*time.Second),
+		conf.Description[time.Duration]("how long to wait before giving up"),
+		conf.Placeholder[time.Duration]("DURATION"),
+	)
+
+	var databaseURL string
+	conf.Var(parser, &databaseURL, "DATABASE_URL", conf.Required[string](true), conf.Description[string]("primary DB DSN"))
+
+	var buf bytes.Buffer
+	parser.Usage(&buf)
+
+	output := buf.String()
+	require.Contains(t, output, "--database-url")
+	require.Contains(t, output, "DATABASE_URL")
+	require.Contains(t, output, "primary DB DSN")
+	require.Contains(t, output, "--timeout <DURATION>")
+	require.Contains(t, output, "how long to wait before giving up")
+	require.Contains(t, output, "5s")
+}
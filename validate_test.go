@@ -0,0 +1,65 @@
+package conf_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/davidmdm/conf"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOneOf(t *testing.T) {
+	values := map[string]string{"LEVEL": "loud"}
+	parser := conf.MakeParser(conf.FromLookup(func(name string) (string, bool) {
+		value, ok := values[name]
+		return value, ok
+	}))
+
+	var level string
+	conf.Var(parser, &level, "LEVEL", conf.OneOf("debug", "info", "warn", "error"))
+
+	require.EqualError(
+		t,
+		parser.Parse(),
+		`failed to parse variable(s): LEVEL: value "loud" not in allowed set [debug info warn error]`,
+	)
+
+	values["LEVEL"] = "warn"
+	require.NoError(t, parser.Parse())
+	require.Equal(t, "warn", level)
+}
+
+func TestValidate(t *testing.T) {
+	values := map[string]string{"PORT": "99999"}
+	parser := conf.MakeParser(conf.FromLookup(func(name string) (string, bool) {
+		value, ok := values[name]
+		return value, ok
+	}))
+
+	var port int
+	conf.Var(parser, &port, "PORT", conf.Validate(func(p int) error {
+		if p < 1 || p > 65535 {
+			return errors.New("port out of range")
+		}
+		return nil
+	}))
+
+	require.EqualError(t, parser.Parse(), "failed to parse variable(s): PORT: port out of range")
+
+	values["PORT"] = "8080"
+	require.NoError(t, parser.Parse())
+	require.Equal(t, 8080, port)
+}
+
+func TestOneOfInUsage(t *testing.T) {
+	parser := conf.MakeParser(conf.FromHelpRequester(conf.CommandLineArgs()))
+
+	var level string
+	conf.Var(parser, &level, "LEVEL", conf.OneOf("debug", "info"))
+
+	var buf bytes.Buffer
+	parser.Usage(&buf)
+
+	require.Contains(t, buf.String(), "[debug info]")
+}
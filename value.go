@@ -2,6 +2,7 @@ package conf
 
 import (
 	"encoding"
+	"encoding/json"
 	"fmt"
 	"reflect"
 	"strconv"
@@ -10,12 +11,27 @@ import (
 )
 
 type value interface {
+	Get() any
+	Type() reflect.Type
 	Parse(string) error
 	Set(any)
 }
 
+var (
+	textUnmarshalerType   = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+	binaryUnmarshalerType = reflect.TypeOf((*encoding.BinaryUnmarshaler)(nil)).Elem()
+)
+
 type genericValue[T any] struct{ dst *T }
 
+func (v genericValue[T]) Get() any {
+	return *v.dst
+}
+
+func (v genericValue[T]) Type() reflect.Type {
+	return reflect.TypeOf((*T)(nil)).Elem()
+}
+
 func (v genericValue[T]) Set(value any) {
 	*v.dst = value.(T)
 }
@@ -31,17 +47,22 @@ func parse(v reflect.Value, text string, topLevel bool) error {
 		if v.IsNil() {
 			v.Set(reflect.New(t.Elem()))
 		}
+		t = t.Elem()
+		v = v.Elem()
+	}
 
-		if unmarshaler, ok := v.Interface().(encoding.TextUnmarshaler); ok {
+	// Check both the dereferenced value and, for addressable values, its pointer, so that
+	// TextUnmarshaler/BinaryUnmarshaler implemented with either receiver is honoured. This
+	// also lets deeply nested destinations (e.g. a map[string]SomeStruct value or a [][]int
+	// element) bypass the "cannot support deep slices/maps" restriction below, as long as the
+	// element type itself knows how to unmarshal text.
+	if v.CanAddr() {
+		if unmarshaler, ok := v.Addr().Interface().(encoding.TextUnmarshaler); ok {
 			return unmarshaler.UnmarshalText([]byte(text))
 		}
-
-		if unmarshaler, ok := v.Interface().(encoding.BinaryUnmarshaler); ok {
+		if unmarshaler, ok := v.Addr().Interface().(encoding.BinaryUnmarshaler); ok {
 			return unmarshaler.UnmarshalBinary([]byte(text))
 		}
-
-		t = t.Elem()
-		v = v.Elem()
 	}
 
 	switch t.Kind() {
@@ -82,15 +103,25 @@ func parse(v reflect.Value, text string, topLevel bool) error {
 		}
 		v.SetFloat(val)
 	case reflect.Slice:
-		if !topLevel {
-			return fmt.Errorf("cannot support deep slices")
-		}
-
 		if t.Elem().Kind() == reflect.Uint8 {
 			v.Set(reflect.ValueOf([]byte(text)))
 			return nil
 		}
 
+		// A JSON array lets a deeply nested slice (e.g. [][]int, or a slice whose element
+		// type has no flat text form) bypass the comma-separated syntax below. Gated on the
+		// element type so a flat slice of scalars (e.g. []string) keeps treating a literal
+		// leading "[" as an ordinary comma-separated item, as it always has.
+		if needsJSONSyntax(t.Elem()) {
+			if trimmed := strings.TrimSpace(text); strings.HasPrefix(trimmed, "[") {
+				return json.Unmarshal([]byte(trimmed), v.Addr().Interface())
+			}
+		}
+
+		if !topLevel {
+			return fmt.Errorf("cannot support deep slices")
+		}
+
 		if strings.TrimSpace(text) == "" {
 			v.Set(reflect.MakeSlice(t, 0, 0))
 			return nil
@@ -107,6 +138,16 @@ func parse(v reflect.Value, text string, topLevel bool) error {
 		v.Set(slice)
 
 	case reflect.Map:
+		// A JSON object lets a deeply nested map (e.g. map[string]SomeStruct) bypass the
+		// comma-separated key=value syntax below. Gated on the value type so a flat map of
+		// scalars (e.g. map[string]int) keeps treating a literal leading "{" as an ordinary
+		// comma-separated entry, as it always has.
+		if needsJSONSyntax(t.Elem()) {
+			if trimmed := strings.TrimSpace(text); strings.HasPrefix(trimmed, "{") {
+				return json.Unmarshal([]byte(trimmed), v.Addr().Interface())
+			}
+		}
+
 		if !topLevel {
 			return fmt.Errorf("cannot support deep maps")
 		}
@@ -143,3 +184,15 @@ func parse(v reflect.Value, text string, topLevel bool) error {
 
 	return nil
 }
+
+// needsJSONSyntax reports whether a slice/map element of type t has no flat text form -
+// i.e. it isn't a scalar and doesn't implement TextUnmarshaler/BinaryUnmarshaler - and so
+// must be decoded as JSON rather than as a comma-separated item.
+func needsJSONSyntax(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Slice, reflect.Map, reflect.Array, reflect.Struct:
+		return !reflect.PointerTo(t).Implements(textUnmarshalerType) && !reflect.PointerTo(t).Implements(binaryUnmarshalerType)
+	default:
+		return false
+	}
+}
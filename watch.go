@@ -0,0 +1,280 @@
+package conf
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// WatchableLookupFunc is a LookupFunc that can also notify subscribers whenever the
+// underlying source it reads from changes, such as a file being rewritten or an
+// environment variable changing between polls. Wrap one with FromWatchable and pass it to
+// MakeParser to register it as both a lookup source and, for Watch, a trigger to re-run
+// Parse. If it also implements Close() (as the sources returned by WatchableFileSystem,
+// WatchableStructuredFile, and WatchableEnviron do), Watch calls Close once its context is
+// done, so the goroutine it runs in the background does not outlive Watch.
+type WatchableLookupFunc interface {
+	Lookup(name string) (string, bool)
+	Subscribe(onChange func())
+}
+
+// Watch subscribes to every WatchableLookupFunc registered with MakeParser and re-runs
+// Parse whenever one of them reports a change, until ctx is done. The returned channel
+// carries the error, if any, from each triggered Parse, and is closed once ctx is done. It
+// returns an error immediately if no watchable source was registered.
+//
+// Each reload writes a watched field's new value while holding that field's internal lock,
+// which the pointer passed to Var does not participate in - reading it directly from
+// another goroutine once Watch is running is a data race. Read a watched field with Get, or
+// observe its new value via OnChange, instead.
+func (parser Parser) Watch(ctx context.Context) (<-chan error, error) {
+	if len(parser.watchables) == 0 {
+		return nil, errors.New("conf: Watch: no watchable lookup source registered")
+	}
+
+	changed := make(chan struct{}, 1)
+	notify := func() {
+		select {
+		case changed <- struct{}{}:
+		default:
+		}
+	}
+
+	for _, watchable := range parser.watchables {
+		watchable.Subscribe(notify)
+	}
+
+	errs := make(chan error)
+	go func() {
+		defer close(errs)
+		defer closeWatchables(parser.watchables)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-changed:
+				if err := parser.Parse(); err != nil {
+					select {
+					case errs <- err:
+					case <-ctx.Done():
+						return
+					}
+				}
+			}
+		}
+	}()
+
+	return errs, nil
+}
+
+// closeWatchables closes every watchable that owns a goroutine or OS resource of its own
+// (WatchableFileSystem and WatchableStructuredFile's fsnotify watcher, WatchableEnviron's
+// poll ticker), so that the background work Watch started does not outlive ctx.
+func closeWatchables(watchables []WatchableLookupFunc) {
+	for _, w := range watchables {
+		if closer, ok := w.(interface{ Close() }); ok {
+			closer.Close()
+		}
+	}
+}
+
+// subscribers is embedded by WatchableLookupFunc implementations to manage their list of
+// change callbacks.
+type subscribers struct {
+	mu   sync.Mutex
+	subs []func()
+}
+
+func (s *subscribers) Subscribe(onChange func()) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.subs = append(s.subs, onChange)
+}
+
+func (s *subscribers) notify() {
+	s.mu.Lock()
+	subs := append([]func(){}, s.subs...)
+	s.mu.Unlock()
+
+	for _, sub := range subs {
+		sub()
+	}
+}
+
+type fileWatchLookup struct {
+	subscribers
+	lookup  LookupFunc
+	watcher *fsnotify.Watcher
+}
+
+func (f *fileWatchLookup) Lookup(name string) (string, bool) {
+	return f.lookup(name)
+}
+
+// Close stops the underlying fsnotify watcher. Parser.Watch calls this once its context is
+// done, so that watching a file doesn't leak its goroutine past Watch's lifetime.
+func (f *fileWatchLookup) Close() {
+	f.watcher.Close()
+}
+
+// watchPath starts an fsnotify watch on path's parent directory, so that edits which
+// replace the file (as editors and k8s configmap mounts do via rename) are still observed,
+// and calls onChange whenever path itself is created, written, or renamed. The returned
+// watcher's Events channel is drained by a background goroutine until the watcher is closed.
+func watchPath(path string, onChange func()) (*fsnotify.Watcher, error) {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+
+	dir := filepath.Dir(path)
+	if err := watcher.Add(dir); err != nil {
+		watcher.Close()
+		return nil, err
+	}
+
+	target := filepath.Clean(path)
+
+	go func() {
+		for event := range watcher.Events {
+			if filepath.Clean(event.Name) != target {
+				continue
+			}
+			if event.Has(fsnotify.Write) || event.Has(fsnotify.Create) || event.Has(fsnotify.Rename) {
+				onChange()
+			}
+		}
+	}()
+
+	return watcher, nil
+}
+
+// watchDir starts an fsnotify watch directly on dir and calls onChange whenever an entry
+// within it is created, written, or renamed - unlike watchPath, which watches a single
+// file's parent. The returned watcher's Events channel is drained by a background goroutine
+// until the watcher is closed.
+func watchDir(dir string, onChange func()) (*fsnotify.Watcher, error) {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := watcher.Add(dir); err != nil {
+		watcher.Close()
+		return nil, err
+	}
+
+	go func() {
+		for event := range watcher.Events {
+			if event.Has(fsnotify.Write) || event.Has(fsnotify.Create) || event.Has(fsnotify.Rename) {
+				onChange()
+			}
+		}
+	}()
+
+	return watcher, nil
+}
+
+// WatchableFileSystem is a watching variant of FileSystem: it behaves the same way for
+// lookups, but also triggers Watch whenever a file under opts.Base changes.
+func WatchableFileSystem(opts FileSystemOptions) WatchableLookupFunc {
+	if opts.Base == "" {
+		opts.Base = "."
+	}
+
+	w := &fileWatchLookup{lookup: FileSystem(opts)}
+	watcher, err := watchDir(opts.Base, w.notify)
+	if err != nil {
+		panic(err)
+	}
+	w.watcher = watcher
+
+	return w
+}
+
+// WatchableStructuredFile is a watching variant of StructuredFile: it behaves the same way
+// for lookups, but also triggers Watch whenever path changes.
+func WatchableStructuredFile(path string, opts StructuredFileOptions) WatchableLookupFunc {
+	base := opts.Base
+	if base == "" {
+		base = "."
+	}
+
+	fullPath := path
+	if !filepath.IsAbs(fullPath) {
+		fullPath = filepath.Join(base, fullPath)
+	}
+
+	w := &fileWatchLookup{lookup: StructuredFile(path, opts)}
+	watcher, err := watchPath(fullPath, w.notify)
+	if err != nil {
+		panic(err)
+	}
+	w.watcher = watcher
+
+	return w
+}
+
+type envPollLookup struct {
+	subscribers
+	done chan struct{}
+}
+
+func (*envPollLookup) Lookup(name string) (string, bool) {
+	return os.LookupEnv(name)
+}
+
+// Close stops the polling goroutine. Parser.Watch calls this once its context is done, so
+// that watching the environment doesn't leak its goroutine past Watch's lifetime.
+func (w *envPollLookup) Close() {
+	close(w.done)
+}
+
+func environSnapshot() map[string]string {
+	env := os.Environ()
+	snapshot := make(map[string]string, len(env))
+	for _, entry := range env {
+		key, value, _ := strings.Cut(entry, "=")
+		snapshot[key] = value
+	}
+	return snapshot
+}
+
+// WatchableEnviron is a watching variant of os.LookupEnv: it polls the process environment
+// every interval (defaulting to 5s) and notifies Watch whenever it differs from the
+// previous poll.
+func WatchableEnviron(interval time.Duration) WatchableLookupFunc {
+	if interval <= 0 {
+		interval = 5 * time.Second
+	}
+
+	w := &envPollLookup{done: make(chan struct{})}
+	last := environSnapshot()
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-w.done:
+				return
+			case <-ticker.C:
+				current := environSnapshot()
+				if !reflect.DeepEqual(current, last) {
+					last = current
+					w.notify()
+				}
+			}
+		}
+	}()
+
+	return w
+}
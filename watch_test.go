@@ -0,0 +1,188 @@
+package conf_test
+
+import (
+	"context"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/davidmdm/conf"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestOnChange(t *testing.T) {
+	values := map[string]string{"NAME": "alice"}
+	parser := conf.MakeParser(conf.FromLookup(func(name string) (string, bool) {
+		value, ok := values[name]
+		return value, ok
+	}))
+
+	var name string
+	conf.Var(parser, &name, "NAME")
+
+	var seen []string
+	conf.OnChange(parser, "NAME", func(old, new string) {
+		seen = append(seen, old+"->"+new)
+	})
+
+	require.NoError(t, parser.Parse())
+	require.Equal(t, "alice", name)
+	require.Equal(t, []string{"->alice"}, seen, "the zero value differs from the parsed value, so the callback fires on first parse")
+
+	// Parsing the same value again must not fire the callback.
+	require.NoError(t, parser.Parse())
+	require.Len(t, seen, 1)
+
+	values["NAME"] = "bob"
+	require.NoError(t, parser.Parse())
+	require.Equal(t, "bob", name)
+	require.Equal(t, []string{"->alice", "alice->bob"}, seen)
+}
+
+func TestOnChangeUnknownField(t *testing.T) {
+	parser := conf.MakeParser(conf.FromLookup(func(string) (string, bool) { return "", false }))
+
+	require.PanicsWithValue(
+		t,
+		`conf: OnChange: no field registered for "MISSING", call Var first`,
+		func() { conf.OnChange(parser, "MISSING", func(old, new string) {}) },
+	)
+}
+
+func TestWatchStructuredFile(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(dir+"/config.yaml", []byte("database:\n  url: first\n"), 0o644))
+
+	parser := conf.MakeParser(conf.FromWatchable(conf.WatchableStructuredFile("config.yaml", conf.StructuredFileOptions{
+		Base:   dir,
+		Decode: yaml.Unmarshal,
+	})))
+
+	var url string
+	conf.Var(parser, &url, "DATABASE_URL")
+
+	var mu sync.Mutex
+	var latest string
+	conf.OnChange(parser, "DATABASE_URL", func(old, new string) {
+		mu.Lock()
+		defer mu.Unlock()
+		latest = new
+	})
+
+	require.NoError(t, parser.Parse())
+	require.Equal(t, "first", url)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	errs, err := parser.Watch(ctx)
+	require.NoError(t, err)
+
+	require.NoError(t, os.WriteFile(dir+"/config.yaml", []byte("database:\n  url: second\n"), 0o644))
+
+	require.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return latest == "second"
+	}, 2*time.Second, 10*time.Millisecond)
+
+	cancel()
+
+	select {
+	case _, ok := <-errs:
+		require.False(t, ok, "errs channel should be closed once ctx is done")
+	case <-time.After(time.Second):
+		t.Fatal("errs channel was not closed after context cancellation")
+	}
+}
+
+func TestWatchableFileSystem(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(dir+"/secret.txt", []byte("first"), 0o644))
+
+	parser := conf.MakeParser(conf.FromWatchable(conf.WatchableFileSystem(conf.FileSystemOptions{Base: dir})))
+
+	var secret string
+	conf.Var(parser, &secret, "secret.txt")
+
+	var mu sync.Mutex
+	var latest string
+	conf.OnChange(parser, "secret.txt", func(old, new string) {
+		mu.Lock()
+		defer mu.Unlock()
+		latest = new
+	})
+
+	require.NoError(t, parser.Parse())
+	require.Equal(t, "first", secret)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	_, err := parser.Watch(ctx)
+	require.NoError(t, err)
+
+	require.NoError(t, os.WriteFile(dir+"/secret.txt", []byte("second"), 0o644))
+
+	require.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return latest == "second"
+	}, 2*time.Second, 10*time.Millisecond, "writing a file under Base should trigger a reload")
+}
+
+func TestGetConcurrentWithWatch(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(dir+"/config.yaml", []byte("database:\n  url: first\n"), 0o644))
+
+	parser := conf.MakeParser(conf.FromWatchable(conf.WatchableStructuredFile("config.yaml", conf.StructuredFileOptions{
+		Base:   dir,
+		Decode: yaml.Unmarshal,
+	})))
+
+	var url string
+	conf.Var(parser, &url, "DATABASE_URL")
+
+	require.NoError(t, parser.Parse())
+	require.Equal(t, "first", conf.Get[string](parser, "DATABASE_URL"))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	_, err := parser.Watch(ctx)
+	require.NoError(t, err)
+
+	stop := make(chan struct{})
+	go func() {
+		defer close(stop)
+		for i := 0; i < 50; i++ {
+			conf.Get[string](parser, "DATABASE_URL")
+		}
+	}()
+
+	require.NoError(t, os.WriteFile(dir+"/config.yaml", []byte("database:\n  url: second\n"), 0o644))
+
+	<-stop
+	require.Eventually(t, func() bool {
+		return conf.Get[string](parser, "DATABASE_URL") == "second"
+	}, 2*time.Second, 10*time.Millisecond)
+}
+
+func TestGetUnknownField(t *testing.T) {
+	parser := conf.MakeParser(conf.FromLookup(func(string) (string, bool) { return "", false }))
+
+	require.PanicsWithValue(
+		t,
+		`conf: Get: no field registered for "MISSING", call Var first`,
+		func() { conf.Get[string](parser, "MISSING") },
+	)
+}
+
+func TestWatchNoSources(t *testing.T) {
+	parser := conf.MakeParser(conf.FromLookup(func(string) (string, bool) { return "", false }))
+
+	_, err := parser.Watch(context.Background())
+	require.EqualError(t, err, "conf: Watch: no watchable lookup source registered")
+}